@@ -0,0 +1,44 @@
+// Package config holds the orchestrator's runtime configuration, populated
+// from the CLI/environment at startup.
+package config
+
+import "time"
+
+// Config holds process-wide settings that don't change between scans.
+type Config struct {
+	// KlarResultListenPort is the port the result HTTP server listens on for
+	// scan job callbacks (klar-scan, trivy-scan, ...).
+	KlarResultListenPort string
+	// ResultServiceAddress is the host:port a scan job's result callback
+	// should POST to in order to reach this orchestrator - typically the
+	// cluster DNS name of the Service fronting it, paired with
+	// KlarResultListenPort.
+	ResultServiceAddress string
+}
+
+// ScanConfig holds the settings for a single Scan call.
+type ScanConfig struct {
+	TargetNamespace   string
+	IgnoredNamespaces []string
+	// RegistrySearch is consulted by imageref.Canonicalize to expand
+	// unqualified image references the way containers.conf's registry
+	// search list does.
+	RegistrySearch []string
+	// ScannerBackend selects the registered scanner.Scanner to use for this
+	// scan; empty falls back to scanner.DefaultBackendName.
+	ScannerBackend string
+	// PerImageScanTimeout bounds how long a single image's scan job may run
+	// before it's reclaimed as failed; zero disables the timeout.
+	PerImageScanTimeout time.Duration
+	// OrchestratorIdleTimeout is how long the orchestrator can go without
+	// handling a result before it reports itself as stalled; zero disables
+	// the stall monitor.
+	OrchestratorIdleTimeout time.Duration
+	// Continuous keeps the scan running against a pod informer instead of
+	// exiting after the initial one-shot listing, launching scans for newly
+	// observed images as they appear.
+	Continuous bool
+	// ResyncPeriod is the pod informer's full resync interval, used only
+	// when Continuous is set.
+	ResyncPeriod time.Duration
+}
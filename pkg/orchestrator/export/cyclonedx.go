@@ -0,0 +1,96 @@
+package export
+
+import "encoding/json"
+
+const cycloneDXSpecVersion = "1.4"
+
+// ToCycloneDX serializes results into a single CycloneDX 1.4 VEX BOM: one
+// "container" component per image, with each image's findings parsed into
+// library components nested under it, and a vulnerabilities section
+// carrying the CVE/severity data against those components. A bare JSON
+// array of BOMs isn't a valid CycloneDX document, so every image must be
+// folded into this one root object rather than emitted as its own BOM.
+func ToCycloneDX(results []ImageResult) ([]byte, error) {
+	bom := cdxBOM{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: cycloneDXSpecVersion,
+		Version:     1,
+		Metadata: cdxMetadata{
+			Component: cdxComponent{
+				Type: "application",
+				Name: "kubei-scan",
+			},
+		},
+	}
+
+	seenComponents := map[string]bool{}
+	for _, image := range results {
+		imageRef := image.ImageName + "@" + image.Digest
+		bom.Components = append(bom.Components, cdxComponent{
+			Type:    "container",
+			Name:    image.ImageName,
+			Version: image.Digest,
+			BOMRef:  imageRef,
+		})
+
+		for _, f := range image.Findings {
+			// Package refs are scoped by imageRef so the same package in two
+			// different images doesn't collide into one component.
+			ref := imageRef + ":" + f.PackageName + "@" + f.PackageVersion
+			if f.PackageName != "" && !seenComponents[ref] {
+				seenComponents[ref] = true
+				bom.Components = append(bom.Components, cdxComponent{
+					Type:    "library",
+					Name:    f.PackageName,
+					Version: f.PackageVersion,
+					BOMRef:  ref,
+				})
+			}
+
+			bom.Vulnerabilities = append(bom.Vulnerabilities, cdxVulnerability{
+				ID:          f.VulnerabilityID,
+				Description: f.Description,
+				Ratings:     []cdxRating{{Severity: f.Severity}},
+				Affects:     []cdxAffects{{Ref: ref}},
+			})
+		}
+	}
+
+	return json.Marshal(bom)
+}
+
+// cdx* types mirror the subset of the CycloneDX 1.4 schema kubei emits.
+type cdxBOM struct {
+	BOMFormat       string             `json:"bomFormat"`
+	SpecVersion     string             `json:"specVersion"`
+	Version         int                `json:"version"`
+	Metadata        cdxMetadata        `json:"metadata"`
+	Components      []cdxComponent     `json:"components,omitempty"`
+	Vulnerabilities []cdxVulnerability `json:"vulnerabilities,omitempty"`
+}
+
+type cdxMetadata struct {
+	Component cdxComponent `json:"component"`
+}
+
+type cdxComponent struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	BOMRef  string `json:"bom-ref,omitempty"`
+}
+
+type cdxVulnerability struct {
+	ID          string       `json:"id"`
+	Description string       `json:"description,omitempty"`
+	Ratings     []cdxRating  `json:"ratings,omitempty"`
+	Affects     []cdxAffects `json:"affects,omitempty"`
+}
+
+type cdxRating struct {
+	Severity string `json:"severity"`
+}
+
+type cdxAffects struct {
+	Ref string `json:"ref"`
+}
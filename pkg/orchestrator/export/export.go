@@ -0,0 +1,39 @@
+// Package export serializes scan results into standard formats (SARIF,
+// CycloneDX) that CI gating and security dashboards already understand, so
+// users don't have to write custom glue around kubei's native result shape.
+//
+// It intentionally has no dependency on the orchestrator package: callers
+// adapt their own result types into ImageResult/Finding at the boundary,
+// which keeps this package a reusable leaf.
+package export
+
+// Format identifies one of the serializations this package supports.
+type Format string
+
+const (
+	FormatJSON      Format = "json"
+	FormatSARIF     Format = "sarif"
+	FormatCycloneDX Format = "cyclonedx"
+)
+
+// Finding is the format-agnostic shape every exporter consumes for a single
+// vulnerability.
+type Finding struct {
+	VulnerabilityID string
+	PackageName     string
+	PackageVersion  string
+	FixedVersion    string
+	Severity        string
+	Description     string
+	Link            string
+}
+
+// ImageResult groups every finding reported for one scanned image/platform
+// variant.
+type ImageResult struct {
+	ImageName    string
+	Digest       string
+	Architecture string
+	OS           string
+	Findings     []Finding
+}
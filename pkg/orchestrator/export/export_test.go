@@ -0,0 +1,170 @@
+package export
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func sampleResults() []ImageResult {
+	return []ImageResult{
+		{
+			ImageName:    "docker.io/library/nginx:latest",
+			Digest:       "sha256:abc123",
+			Architecture: "amd64",
+			OS:           "linux",
+			Findings: []Finding{
+				{
+					VulnerabilityID: "CVE-2024-0001",
+					PackageName:     "openssl",
+					PackageVersion:  "1.1.1",
+					FixedVersion:    "1.1.2",
+					Severity:        "High",
+					Description:     "example vulnerability",
+					Link:            "https://example.com/CVE-2024-0001",
+				},
+			},
+		},
+	}
+}
+
+func TestToSARIF(t *testing.T) {
+	body, err := ToSARIF(sampleResults())
+	if err != nil {
+		t.Fatalf("ToSARIF returned error: %v", err)
+	}
+
+	var doc sarifLog
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("failed to unmarshal SARIF output: %v", err)
+	}
+
+	if doc.Version != sarifVersion {
+		t.Errorf("version = %q, want %q", doc.Version, sarifVersion)
+	}
+	if len(doc.Runs) != 1 {
+		t.Fatalf("runs = %d, want 1", len(doc.Runs))
+	}
+
+	results := doc.Runs[0].Results
+	if len(results) != 1 {
+		t.Fatalf("results = %d, want 1", len(results))
+	}
+	if results[0].RuleID != "CVE-2024-0001" {
+		t.Errorf("ruleId = %q, want %q", results[0].RuleID, "CVE-2024-0001")
+	}
+	if results[0].Level != "error" {
+		t.Errorf("level = %q, want %q for High severity", results[0].Level, "error")
+	}
+	wantURI := "pkg:oci/docker.io/library/nginx:latest@sha256:abc123"
+	if got := results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI; got != wantURI {
+		t.Errorf("artifact location = %q, want %q", got, wantURI)
+	}
+}
+
+func TestSeverityToSARIFLevel(t *testing.T) {
+	tests := map[string]string{
+		"Critical": "error",
+		"high":     "error",
+		"Medium":   "warning",
+		"moderate": "warning",
+		"low":      "note",
+		"unknown":  "note",
+		"":         "note",
+		"bogus":    "none",
+	}
+
+	for severity, want := range tests {
+		if got := severityToSARIFLevel(severity); got != want {
+			t.Errorf("severityToSARIFLevel(%q) = %q, want %q", severity, got, want)
+		}
+	}
+}
+
+func TestToCycloneDX(t *testing.T) {
+	body, err := ToCycloneDX(sampleResults())
+	if err != nil {
+		t.Fatalf("ToCycloneDX returned error: %v", err)
+	}
+
+	var bom cdxBOM
+	if err := json.Unmarshal(body, &bom); err != nil {
+		t.Fatalf("failed to unmarshal CycloneDX output: %v", err)
+	}
+
+	if bom.BOMFormat != "CycloneDX" || bom.SpecVersion != cycloneDXSpecVersion {
+		t.Errorf("unexpected BOM header: %+v", bom)
+	}
+	// One container component per image, plus one library component per
+	// distinct package within each image.
+	if len(bom.Components) != 2 || bom.Components[0].Name != "docker.io/library/nginx:latest" || bom.Components[1].Name != "openssl" {
+		t.Errorf("components = %+v, want one container component and one openssl component", bom.Components)
+	}
+	if len(bom.Vulnerabilities) != 1 || bom.Vulnerabilities[0].ID != "CVE-2024-0001" {
+		t.Errorf("vulnerabilities = %+v, want one CVE-2024-0001 entry", bom.Vulnerabilities)
+	}
+}
+
+func TestToCycloneDXSkipsDuplicateComponents(t *testing.T) {
+	results := sampleResults()
+	results[0].Findings = append(results[0].Findings, Finding{
+		VulnerabilityID: "CVE-2024-0002",
+		PackageName:     "openssl",
+		PackageVersion:  "1.1.1",
+		Severity:        "Medium",
+	})
+
+	body, err := ToCycloneDX(results)
+	if err != nil {
+		t.Fatalf("ToCycloneDX returned error: %v", err)
+	}
+
+	var bom cdxBOM
+	if err := json.Unmarshal(body, &bom); err != nil {
+		t.Fatalf("failed to unmarshal CycloneDX output: %v", err)
+	}
+
+	if len(bom.Components) != 2 {
+		t.Errorf("components = %d, want 1 container + 1 deduplicated library component", len(bom.Components))
+	}
+	if len(bom.Vulnerabilities) != 2 {
+		t.Errorf("vulnerabilities = %d, want 2", len(bom.Vulnerabilities))
+	}
+}
+
+// TestToCycloneDXScopesComponentsPerImage verifies that the same package
+// name/version reported for two different images produces two distinct
+// components, since they are scoped by imageRef and not deduplicated
+// across images.
+func TestToCycloneDXScopesComponentsPerImage(t *testing.T) {
+	results := sampleResults()
+	results = append(results, ImageResult{
+		ImageName: "docker.io/library/redis:latest",
+		Digest:    "sha256:def456",
+		Findings: []Finding{
+			{
+				VulnerabilityID: "CVE-2024-0003",
+				PackageName:     "openssl",
+				PackageVersion:  "1.1.1",
+				Severity:        "High",
+			},
+		},
+	})
+
+	body, err := ToCycloneDX(results)
+	if err != nil {
+		t.Fatalf("ToCycloneDX returned error: %v", err)
+	}
+
+	var bom cdxBOM
+	if err := json.Unmarshal(body, &bom); err != nil {
+		t.Fatalf("failed to unmarshal CycloneDX output: %v", err)
+	}
+
+	// 2 container components + 1 openssl component per image = 4.
+	if len(bom.Components) != 4 {
+		t.Errorf("components = %d, want 4 (2 containers + 2 per-image openssl components)", len(bom.Components))
+	}
+	if len(bom.Vulnerabilities) != 2 {
+		t.Errorf("vulnerabilities = %d, want 2", len(bom.Vulnerabilities))
+	}
+}
@@ -0,0 +1,115 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	sarifSchema  = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+// ToSARIF serializes results into a SARIF 2.1.0 log: each finding becomes a
+// result with ruleId=<VulnerabilityID>, a level derived from severity, and a
+// location pointing at the image's pkg:oci PURL.
+func ToSARIF(results []ImageResult) ([]byte, error) {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "kubei",
+				InformationURI: "https://github.com/Portshift/kubei",
+			},
+		},
+	}
+
+	for _, image := range results {
+		purl := ociPURL(image)
+		for _, f := range image.Findings {
+			run.Results = append(run.Results, sarifResult{
+				RuleID:  f.VulnerabilityID,
+				Level:   severityToSARIFLevel(f.Severity),
+				Message: sarifMessage{Text: f.Description},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: purl},
+					},
+				}},
+			})
+		}
+	}
+
+	doc := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}
+
+	return json.Marshal(doc)
+}
+
+func ociPURL(image ImageResult) string {
+	if image.Digest != "" {
+		return fmt.Sprintf("pkg:oci/%s@%s", image.ImageName, image.Digest)
+	}
+
+	return fmt.Sprintf("pkg:oci/%s", image.ImageName)
+}
+
+func severityToSARIFLevel(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical", "high":
+		return "error"
+	case "medium", "moderate":
+		return "warning"
+	case "low", "negligible", "unknown", "":
+		return "note"
+	default:
+		return "none"
+	}
+}
+
+// sarif* types mirror the subset of the SARIF 2.1.0 schema kubei emits.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string `json:"name"`
+	InformationURI string `json:"informationUri"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
@@ -0,0 +1,59 @@
+// Package imageref canonicalizes container image references the way
+// containers.conf's registry search list does, so that e.g. "nginx",
+// "docker.io/library/nginx" and "docker.io/library/nginx:latest" are
+// recognized as the same image instead of producing independent scans.
+package imageref
+
+import "strings"
+
+// Canonicalize expands an unqualified image reference against
+// searchRegistries: a reference with no registry host gets the first entry
+// of searchRegistries as its registry, a docker.io repository with no
+// namespace gets the implicit "library/" prefix, and a reference with no
+// explicit tag gets ":latest" appended. References already pinned by digest
+// are returned unchanged, since they're already maximally specific.
+func Canonicalize(ref string, searchRegistries []string) string {
+	if ref == "" || strings.Contains(ref, "@") {
+		return ref
+	}
+
+	registry, repository, tag := splitReference(ref)
+
+	if registry == "" {
+		registry = "docker.io"
+		if len(searchRegistries) > 0 {
+			registry = searchRegistries[0]
+		}
+	}
+
+	if registry == "docker.io" && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+
+	if tag == "" {
+		tag = "latest"
+	}
+
+	return registry + "/" + repository + ":" + tag
+}
+
+// splitReference pulls the registry host (when the leading path segment
+// looks like one), the repository path and the tag (if any) out of ref.
+func splitReference(ref string) (registry, repository, tag string) {
+	name := ref
+	if idx := strings.LastIndex(name, ":"); idx > strings.LastIndex(name, "/") {
+		tag = name[idx+1:]
+		name = name[:idx]
+	}
+
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 2 && looksLikeRegistry(parts[0]) {
+		return parts[0], parts[1], tag
+	}
+
+	return "", name, tag
+}
+
+func looksLikeRegistry(hostCandidate string) bool {
+	return hostCandidate == "localhost" || strings.ContainsAny(hostCandidate, ".:")
+}
@@ -0,0 +1,68 @@
+package imageref
+
+import "testing"
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name             string
+		ref              string
+		searchRegistries []string
+		want             string
+	}{
+		{
+			name: "bare name",
+			ref:  "nginx",
+			want: "docker.io/library/nginx:latest",
+		},
+		{
+			name: "docker.io without library prefix",
+			ref:  "docker.io/nginx",
+			want: "docker.io/library/nginx:latest",
+		},
+		{
+			name: "docker.io with library prefix and tag",
+			ref:  "docker.io/library/nginx:1.21",
+			want: "docker.io/library/nginx:1.21",
+		},
+		{
+			name: "explicit registry host",
+			ref:  "quay.io/coreos/etcd",
+			want: "quay.io/coreos/etcd:latest",
+		},
+		{
+			name: "explicit registry host with port",
+			ref:  "localhost:5000/myapp:v1",
+			want: "localhost:5000/myapp:v1",
+		},
+		{
+			name: "digest pin returned unchanged",
+			ref:  "nginx@sha256:deadbeef",
+			want: "nginx@sha256:deadbeef",
+		},
+		{
+			name:             "unqualified name uses first search registry",
+			ref:              "myapp",
+			searchRegistries: []string{"registry.internal"},
+			want:             "registry.internal/myapp:latest",
+		},
+		{
+			name: "explicit tag preserved",
+			ref:  "quay.io/coreos/etcd:v3",
+			want: "quay.io/coreos/etcd:v3",
+		},
+		{
+			name: "empty reference returned unchanged",
+			ref:  "",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Canonicalize(tt.ref, tt.searchRegistries)
+			if got != tt.want {
+				t.Errorf("Canonicalize(%q, %v) = %q, want %q", tt.ref, tt.searchRegistries, got, tt.want)
+			}
+		})
+	}
+}
@@ -4,9 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"github.com/Portshift/klar/clair"
-	"github.com/Portshift/klar/forwarding"
 	"github.com/Portshift/kubei/pkg/config"
+	"github.com/Portshift/kubei/pkg/orchestrator/export"
+	"github.com/Portshift/kubei/pkg/orchestrator/imageref"
+	"github.com/Portshift/kubei/pkg/orchestrator/scanner"
 	k8s_utils "github.com/Portshift/kubei/pkg/utils/k8s"
 	slice_utils "github.com/Portshift/kubei/pkg/utils/slice"
 	uuid "github.com/satori/go.uuid"
@@ -14,19 +15,44 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 	"net/http"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 type Orchestrator struct {
 	imageToScanData map[string]*scanData
-	progress        ScanProgress
-	status          Status
-	config          *config.Config
-	scanConfig      *config.ScanConfig
-	clientset       kubernetes.Interface
-	server          *http.Server
+	// scanUUIDToScanData indexes the same scanData values by scan UUID.
+	// imageToScanData is now keyed by resolved digest rather than image
+	// name, but scan backends report results by scan UUID, so results are
+	// matched through this index instead of by digest or image name.
+	scanUUIDToScanData map[string]*scanData
+	progress           ScanProgress
+	status             Status
+	config             *config.Config
+	scanConfig         *config.ScanConfig
+	clientset          kubernetes.Interface
+	server             *http.Server
+	// scanner is the backend resolved from ScanConfig.ScannerBackend for the
+	// current scan (Klar/Clair by default; see pkg/orchestrator/scanner).
+	scanner scanner.Scanner
+	// scanQueue carries newly discovered scanData to jobBatchManagement. In
+	// one-shot mode it is filled once by initScan; in continuous mode it is
+	// also fed by the pod informer as new images show up.
+	scanQueue chan *scanData
+	// stopWatching is closed to tear down the pod informer started by Scan
+	// in continuous mode.
+	stopWatching chan struct{}
+	// stopStallMonitor is closed to stop the orchestrator-wide idle monitor
+	// started by Scan.
+	stopStallMonitor chan struct{}
+	// lastResultAt is the time the last scan result was handled. It feeds
+	// the stall monitor, which flips the orchestrator to ScanStalled if it
+	// goes untouched for too long.
+	lastResultAt time.Time
 	sync.Mutex
 }
 
@@ -41,6 +67,11 @@ type VulnerabilitiesScanner interface {
 }
 
 type imagePodContext struct {
+	// imageName is the verbatim reference as it appears on the container
+	// spec (e.g. "nginx"), kept for human-friendly reporting even though the
+	// owning scanData may be keyed by a canonicalized/digest form shared
+	// with other, differently-written references to the same image.
+	imageName       string
 	containerName   string
 	podName         string
 	namespace       string
@@ -50,13 +81,37 @@ type imagePodContext struct {
 }
 
 type scanData struct {
-	imageName  string
-	contexts   []*imagePodContext // All the pods that contain this image
-	scanUUID   string
-	result     []*clair.Vulnerability
-	resultChan chan bool
-	success    bool
-	completed  bool
+	imageName string
+	// digest, architecture and os identify the specific manifest-list
+	// variant this scanData covers, so results for e.g. the arm64 and
+	// amd64 builds of the same tag are never conflated. digest is empty
+	// when no per-platform digest could be resolved.
+	digest       string
+	architecture string
+	os           string
+	contexts     []*imagePodContext // All the pods that contain this image
+	scanUUID     string
+	result       []*scanner.ScanFinding
+	resultChan   chan bool
+	success      bool
+	completed    bool
+	// timeoutTimer fires if no result is handled for this image within
+	// ScanConfig.PerImageScanTimeout, reclaiming the scan so a stuck
+	// scan job can't hang the orchestrator forever.
+	timeoutTimer *time.Timer
+}
+
+const scanTimeoutVulnerabilityID = "KUBEI-SCAN-TIMEOUT"
+
+// newScanTimeoutFinding synthesizes a finding that surfaces a reclaimed,
+// stuck scan through the normal results path instead of silently dropping
+// the image.
+func newScanTimeoutFinding(imageName string) *scanner.ScanFinding {
+	return &scanner.ScanFinding{
+		VulnerabilityID: scanTimeoutVulnerabilityID,
+		Severity:        "Unknown",
+		Description:     fmt.Sprintf("Scan of image %v timed out before a result was received from the scan job", imageName),
+	}
 }
 
 const (
@@ -78,7 +133,9 @@ func shouldIgnorePod(pod *corev1.Pod, ignoredNamespaces []string) bool {
 }
 
 func (o *Orchestrator) initScan() error {
+	o.Lock()
 	o.status = ScanInit
+	o.Unlock()
 
 	// Get all target pods
 	podList, err := o.clientset.CoreV1().Pods(o.scanConfig.TargetNamespace).List(metav1.ListOptions{})
@@ -103,42 +160,109 @@ func (o *Orchestrator) initScan() error {
 		}
 
 		for _, container := range pod.Spec.Containers {
-			// Create pod context
-			podContext := &imagePodContext{
-				containerName:   container.Name,
-				podName:         pod.GetName(),
-				podUid:          string(pod.GetUID()),
-				namespace:       pod.GetNamespace(),
-				imagePullSecret: k8s_utils.GetMatchingSecretName(secrets, container.Image),
-				imageHash:       getImageHash(containerNameToImageId, container),
-			}
-			if data, ok := imageToScanData[container.Image]; !ok {
-				// Image added for the first time, create scan data and append pod context
-				imageToScanData[container.Image] = &scanData{
-					imageName:  container.Image,
-					contexts:   []*imagePodContext{podContext},
-					scanUUID:   uuid.NewV4().String(),
-					resultChan: make(chan bool),
+			pullSecret := k8s_utils.GetMatchingSecretName(secrets, container.Image)
+			imageHash := getImageHash(containerNameToImageId, container)
+			canonicalImage := imageref.Canonicalize(container.Image, o.scanConfig.RegistrySearch)
+
+			for _, target := range o.resolveScanTargets(canonicalImage, imageHash, pullSecret, pod.GetNamespace()) {
+				// Create pod context
+				podContext := &imagePodContext{
+					imageName:       container.Image,
+					containerName:   container.Name,
+					podName:         pod.GetName(),
+					podUid:          string(pod.GetUID()),
+					namespace:       pod.GetNamespace(),
+					imagePullSecret: pullSecret,
+					imageHash:       target.digest,
+				}
+
+				key := scanKey(canonicalImage, target)
+				if data, ok := imageToScanData[key]; !ok {
+					// Image/platform variant seen for the first time, create scan data and append pod context
+					imageToScanData[key] = &scanData{
+						imageName:    canonicalImage,
+						digest:       target.digest,
+						architecture: target.architecture,
+						os:           target.os,
+						contexts:     []*imagePodContext{podContext},
+						scanUUID:     uuid.NewV4().String(),
+						resultChan:   make(chan bool),
+					}
+				} else {
+					// Image/platform variant already exists in map, just append the pod context
+					data.contexts = append(data.contexts, podContext)
 				}
-			} else {
-				// Image already exist in map, just append the pod context
-				data.contexts = append(data.contexts, podContext)
 			}
 		}
 	}
 
+	o.Lock()
 	o.imageToScanData = imageToScanData
+	o.scanUUIDToScanData = make(map[string]*scanData, len(imageToScanData))
+	o.scanQueue = make(chan *scanData, len(imageToScanData))
+	for _, data := range imageToScanData {
+		o.scanUUIDToScanData[data.scanUUID] = data
+		o.queueScan(data)
+	}
 	o.progress = ScanProgress{
 		ImagesToScan:          uint32(len(imageToScanData)),
 		ImagesStartedToScan:   0,
 		ImagesCompletedToScan: 0,
 	}
+	o.Unlock()
 
 	log.Infof("Total %d unique images to scan", o.progress.ImagesToScan)
 
 	return nil
 }
 
+// manifestDescriptor identifies a single platform variant of an image. An
+// empty digest means the variant could not be resolved, and the caller
+// should fall back to keying the scan by image name.
+type manifestDescriptor struct {
+	digest       string
+	architecture string
+	os           string
+}
+
+// resolveScanTargets returns one manifestDescriptor per platform variant
+// that should be scanned for an image. When the pod's resolved image hash is
+// known (the common case, once ContainerStatuses is populated) there is
+// exactly one target - the digest the node actually pulled. When it is
+// missing, e.g. for a pod the informer just saw created, the registry's
+// manifest list is consulted - authenticating with pullSecret out of
+// namespace when one is set - and one target is returned per platform it
+// advertises, so a multi-arch tag doesn't collapse onto a single variant.
+func (o *Orchestrator) resolveScanTargets(imageName, imageHash, pullSecret, namespace string) []manifestDescriptor {
+	if imageHash != "" {
+		return []manifestDescriptor{{digest: imageHash}}
+	}
+
+	descriptors, err := k8s_utils.ResolveImageManifestDescriptors(o.clientset, imageName, namespace, pullSecret)
+	if err != nil || len(descriptors) == 0 {
+		log.Warnf("Failed to resolve manifest descriptors, falling back to image name as scan key. image=%v: %v", imageName, err)
+		return []manifestDescriptor{{}}
+	}
+
+	targets := make([]manifestDescriptor, 0, len(descriptors))
+	for _, d := range descriptors {
+		targets = append(targets, manifestDescriptor{digest: d.Digest, architecture: d.Architecture, os: d.OS})
+	}
+
+	return targets
+}
+
+// scanKey is the imageToScanData key for a given manifest variant: its
+// digest when known, or the bare image name as a last resort so variants
+// that could not be resolved still get scanned once rather than dropped.
+func scanKey(imageName string, target manifestDescriptor) string {
+	if target.digest != "" {
+		return target.digest
+	}
+
+	return imageName
+}
+
 func getImageHash(containerNameToImageId map[string]string, container corev1.Container) string {
 	imageID, ok := containerNameToImageId[container.Name]
 	if !ok {
@@ -165,69 +289,282 @@ func Create(config *config.Config) *Orchestrator {
 	}
 
 	http.HandleFunc("/result/", o.resultHttpHandler)
+	http.HandleFunc("/results", o.resultsHttpHandler)
+	http.HandleFunc("/results.sarif", o.resultsHttpHandler)
+	http.HandleFunc("/results.cyclonedx.json", o.resultsHttpHandler)
 
 	return o
 }
 
-func readResultBodyData(req *http.Request) (*forwarding.ImageVulnerabilities, error) {
-	decoder := json.NewDecoder(req.Body)
-	var bodyData *forwarding.ImageVulnerabilities
-	err := decoder.Decode(&bodyData)
+// resultsHttpHandler serves the current ScanResults as JSON, SARIF or
+// CycloneDX. The format is picked from the request path's extension where
+// present (/results.sarif, /results.cyclonedx.json), falling back to
+// content-negotiation on the Accept header for the extension-less /results.
+func (o *Orchestrator) resultsHttpHandler(w http.ResponseWriter, r *http.Request) {
+	results := o.Results()
+
+	var body []byte
+	var contentType string
+	var err error
+
+	switch exportFormatFor(r.URL.Path, r.Header.Get("Accept")) {
+	case export.FormatSARIF:
+		contentType = "application/sarif+json"
+		body, err = export.ToSARIF(toExportImageResults(results))
+	case export.FormatCycloneDX:
+		contentType = "application/vnd.cyclonedx+json"
+		body, err = export.ToCycloneDX(toExportImageResults(results))
+	default:
+		contentType = "application/json"
+		body, err = json.Marshal(results)
+	}
+
 	if err != nil {
+		log.Errorf("Failed to serialize results. err=%v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Write(body)
+}
+
+func exportFormatFor(path, accept string) export.Format {
+	switch {
+	case strings.HasSuffix(path, ".sarif"):
+		return export.FormatSARIF
+	case strings.HasSuffix(path, ".cyclonedx.json"):
+		return export.FormatCycloneDX
+	case strings.Contains(accept, "sarif"):
+		return export.FormatSARIF
+	case strings.Contains(accept, "cyclonedx"):
+		return export.FormatCycloneDX
+	default:
+		return export.FormatJSON
+	}
+}
+
+// toExportImageResults flattens ScanResults (one entry per pod context) into
+// one export.ImageResult per unique image, the shape the export package's
+// serializers expect. Results are grouped by digest alone when one is known,
+// since the same digest is legitimately reported under several different
+// raw image names (one per pod context); grouping by name too would emit a
+// redundant container component per name variant for what
+// imageref.Canonicalize already recognizes as the same image. Every pod
+// context of one image carries the same findings, so they are taken from
+// the first entry of each group only.
+func toExportImageResults(results *ScanResults) []export.ImageResult {
+	seen := make(map[string]*export.ImageResult)
+	order := make([]string, 0, len(results.ImageScanResults))
+
+	for _, r := range results.ImageScanResults {
+		key := r.Digest
+		if key == "" {
+			key = r.ImageName
+		}
+
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		image := &export.ImageResult{
+			ImageName:    r.ImageName,
+			Digest:       r.Digest,
+			Architecture: r.Architecture,
+			OS:           r.OS,
+		}
+		seen[key] = image
+		order = append(order, key)
+
+		for _, v := range r.Vulnerabilities {
+			image.Findings = append(image.Findings, export.Finding{
+				VulnerabilityID: v.VulnerabilityID,
+				PackageName:     v.PackageName,
+				PackageVersion:  v.PackageVersion,
+				FixedVersion:    v.FixedVersion,
+				Severity:        v.Severity,
+				Description:     v.Description,
+				Link:            v.Link,
+			})
+		}
+	}
+
+	imageResults := make([]export.ImageResult, 0, len(order))
+	for _, key := range order {
+		imageResults = append(imageResults, *seen[key])
+	}
+
+	return imageResults
+}
+
+// backendResult is the orchestrator-internal, backend-agnostic view of a
+// result payload, after a Scanner has decoded and normalized it.
+type backendResult struct {
+	image    string
+	scanUUID string
+	success  bool
+	findings []*scanner.ScanFinding
+}
+
+func readResultBodyData(req *http.Request, s scanner.Scanner) (*backendResult, error) {
+	schema := s.ResultSchema()
+	decoder := json.NewDecoder(req.Body)
+	if err := decoder.Decode(schema); err != nil {
 		return nil, fmt.Errorf("failed to decode result: %v", err)
 	}
 
-	return bodyData, nil
+	meta, ok := schema.(scanner.ResultMeta)
+	if !ok {
+		return nil, fmt.Errorf("scanner backend %q result does not implement ResultMeta", s.Name())
+	}
+
+	return &backendResult{
+		image:    meta.ScanImage(),
+		scanUUID: meta.ScanID(),
+		success:  meta.ScanSuccess(),
+		findings: s.Normalize(schema),
+	}, nil
 }
 
-func (o *Orchestrator) handleResult(result *forwarding.ImageVulnerabilities) error {
-	scanD, ok := o.imageToScanData[result.Image]
-	if !ok || scanD == nil {
-		return fmt.Errorf("no scan data for image '%v'", result.Image)
+// queueScan hands data off to jobBatchManagement and, if configured, starts
+// the per-image timeout tracker for it. The send onto scanQueue happens on
+// its own goroutine rather than inline: queueScan is called from
+// handlePodUpsert while holding o.Lock, and the one-shot scan's initial
+// image count is no bound at all on how many images a long-running
+// continuous watch discovers, so a blocking send here would eventually wedge
+// the orchestrator mutex behind a full channel.
+func (o *Orchestrator) queueScan(data *scanData) {
+	go func() { o.scanQueue <- data }()
+
+	if o.scanConfig.PerImageScanTimeout <= 0 {
+		return
 	}
+	data.timeoutTimer = time.AfterFunc(o.scanConfig.PerImageScanTimeout, func() {
+		o.handleScanTimeout(data)
+	})
+}
+
+// handleScanTimeout reclaims a scan whose per-image deadline passed without
+// a result ever arriving, e.g. because the klar-scan pod crashed or its
+// result POST never reached the orchestrator.
+func (o *Orchestrator) handleScanTimeout(data *scanData) {
+	o.Lock()
+	defer o.Unlock()
+
+	if data.completed {
+		return
+	}
+
+	log.Warnf("Scan of image %v timed out, reclaiming it as failed.", data.imageName)
 
-	if result.ScanUUID != scanD.scanUUID {
-		log.Warnf("Scan UUID mismatch. image=%v, received=%v, expected=%v", result.Image, result.ScanUUID, scanD.scanUUID)
+	data.completed = true
+	data.success = false
+	data.result = []*scanner.ScanFinding{newScanTimeoutFinding(data.imageName)}
+
+	// Signaling resultChan is enough: the per-image waiter in
+	// jobBatchManagement is what bumps ImagesCompletedToScan, the same as it
+	// does for a normal result, so a timeout isn't counted twice.
+	select {
+	case data.resultChan <- true:
+	default:
+		log.Warnf("Failed to notify upon scan timeout. image=%v, scan-uuid=%v", data.imageName, data.scanUUID)
+	}
+}
+
+// monitorStall watches for the orchestrator going quiet while a scan is in
+// progress: if no result is handled for ScanConfig.OrchestratorIdleTimeout,
+// it flips the status to ScanStalled so operators know to go look at the
+// klar-scan pods for the images still outstanding, surfaced via Results().
+func (o *Orchestrator) monitorStall(stopCh <-chan struct{}) {
+	if o.scanConfig.OrchestratorIdleTimeout <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(o.scanConfig.OrchestratorIdleTimeout / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			o.Lock()
+			if o.status == Scanning && time.Since(o.lastResultAt) >= o.scanConfig.OrchestratorIdleTimeout {
+				log.Warnf("No scan results received for %v, marking orchestrator as stalled.", o.scanConfig.OrchestratorIdleTimeout)
+				o.status = ScanStalled
+			}
+			o.Unlock()
+		}
+	}
+}
+
+func (o *Orchestrator) handleResult(result *backendResult) error {
+	scanD, ok := o.scanUUIDToScanData[result.scanUUID]
+	if !ok || scanD == nil {
+		// In continuous mode the pods backing this image may have been
+		// deleted (and the scanData with them) while the scan job was still
+		// running. That's expected, not an error - just drop the result.
+		log.Warnf("Dropping result for unknown scan, it may have been descheduled. image=%v, scan-uuid=%v", result.image, result.scanUUID)
 		return nil
 	}
 
 	if scanD.completed {
-		log.Warnf("Duplicate result for image scan. image=%v, scan uuid=%v", result.Image, result.ScanUUID)
+		log.Warnf("Duplicate result for image scan. image=%v, scan uuid=%v", result.image, result.scanUUID)
 		return nil
 	}
 
 	scanD.completed = true
-	scanD.result = result.Vulnerabilities
-	scanD.success = result.Success
+	scanD.result = result.findings
+	scanD.success = result.success
+	if scanD.timeoutTimer != nil {
+		scanD.timeoutTimer.Stop()
+	}
+
+	o.lastResultAt = time.Now()
+	if o.status == ScanStalled {
+		o.status = Scanning
+	}
 
 	if scanD.success && scanD.result == nil {
-		log.Infof("No vulnerabilities found on image %v.", result.Image)
+		log.Infof("No vulnerabilities found on image %v.", result.image)
 	}
 	if !scanD.success {
-		log.Warnf("Scan of image %v has failed! See klar-scan pod logs for more info.", result.Image)
+		log.Warnf("Scan of image %v has failed! See scan job logs for more info.", result.image)
 	}
 
 	select {
 	case scanD.resultChan <- true:
 	default:
-		log.Warnf("Failed to notify upon received result scan. image=%v, scan-uuid=%v", result.Image, result.ScanUUID)
+		log.Warnf("Failed to notify upon received result scan. image=%v, scan-uuid=%v", result.image, result.scanUUID)
 	}
 
 	return nil
 }
 
+// resultHttpHandler is registered on /result/ as a subtree, so it serves
+// every backend-specific callback path (/result/klar/, /result/trivy/, ...)
+// and dispatches to whichever Scanner is registered under that path segment.
 func (o *Orchestrator) resultHttpHandler(w http.ResponseWriter, r *http.Request) {
+	backendName := strings.Trim(strings.TrimPrefix(r.URL.Path, "/result/"), "/")
+
+	s, err := scanner.Get(backendName)
+	if err != nil {
+		log.Errorf("Unknown scanner backend. backend=%v: %v", backendName, err)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
 	o.Lock()
 	defer o.Unlock()
 
-	result, err := readResultBodyData(r)
+	result, err := readResultBodyData(r, s)
 	if err != nil || result == nil {
 		log.Errorf("Invalid result. err=%v, result=%+v", err, result)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
-	log.Debugf("Result was received. image=%+v, success=%+v, scanUUID=%+v",
-		result.Image, result.Success, result.ScanUUID)
+	log.Debugf("Result was received. backend=%v, image=%+v, success=%+v, scanUUID=%+v",
+		backendName, result.image, result.success, result.scanUUID)
 
 	err = o.handleResult(result)
 	if err != nil {
@@ -236,7 +573,7 @@ func (o *Orchestrator) resultHttpHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	log.Debugf("Result was added successfully. image=%+v", result.Image)
+	log.Debugf("Result was added successfully. image=%+v", result.image)
 	w.WriteHeader(http.StatusAccepted)
 }
 
@@ -256,6 +593,15 @@ func (o *Orchestrator) Start() error {
 
 func (o *Orchestrator) Stop() {
 	log.Infof("Stopping Orchestrator server")
+	if o.stopWatching != nil {
+		close(o.stopWatching)
+		o.stopWatching = nil
+	}
+	if o.stopStallMonitor != nil {
+		close(o.stopStallMonitor)
+		o.stopStallMonitor = nil
+	}
+	o.stopAllScanTimers()
 	if o.server != nil {
 		if err := o.server.Shutdown(context.Background()); err != nil {
 			log.Errorf("Failed to shutdown server: %v", err)
@@ -265,22 +611,286 @@ func (o *Orchestrator) Stop() {
 
 func (o *Orchestrator) Scan(scanConfig *config.ScanConfig) error {
 	o.Lock()
-	defer o.Unlock()
-
 	o.scanConfig = scanConfig
-	log.Infof("Start scanning...")
-	err := o.initScan()
+
+	s, err := scanner.Get(scanConfig.ScannerBackend)
 	if err != nil {
 		o.status = ScanInitFailure
+		o.Unlock()
+		return fmt.Errorf("failed to resolve scanner backend: %v", err)
+	}
+	o.scanner = s
+	o.Unlock()
+
+	// initScan resolves manifest descriptors over the network for images
+	// whose digest isn't known yet, so it only takes o.Lock for the map
+	// commit at the end rather than for its whole body - otherwise a slow
+	// registry round trip here would block Status/Results/Stop for as long
+	// as the initial listing takes.
+	log.Infof("Start scanning using the %q backend...", s.Name())
+	if err := o.initScan(); err != nil {
+		o.Lock()
+		o.status = ScanInitFailure
+		o.Unlock()
 		return fmt.Errorf("failed to initiate scan: %v", err)
 	}
 
-	o.status = Scanning
+	o.Lock()
+	defer o.Unlock()
+
 	go o.jobBatchManagement()
 
+	o.lastResultAt = time.Now()
+	o.stopStallMonitor = make(chan struct{})
+	go o.monitorStall(o.stopStallMonitor)
+
+	if scanConfig.Continuous {
+		o.stopWatching = make(chan struct{})
+		go o.watchPods(o.stopWatching)
+		o.status = Watching
+	} else {
+		o.status = Scanning
+	}
+
 	return nil
 }
 
+// watchPods keeps imageToScanData in sync with the cluster for as long as
+// stopCh is open. It is only started when ScanConfig.Continuous is set, and
+// complements the one-shot listing done by initScan: pods created after the
+// initial scan still get their images queued, and pods that disappear have
+// their contexts (and, once orphaned, their scanData) removed.
+func (o *Orchestrator) watchPods(stopCh <-chan struct{}) {
+	factory := k8s_utils.NewPodSharedInformerFactory(o.clientset, o.scanConfig.TargetNamespace, o.scanConfig.ResyncPeriod)
+	informer := factory.Core().V1().Pods().Informer()
+
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if pod, ok := obj.(*corev1.Pod); ok {
+				o.handlePodUpsert(pod)
+			}
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			if pod, ok := newObj.(*corev1.Pod); ok {
+				o.handlePodUpsert(pod)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			pod, ok := obj.(*corev1.Pod)
+			if !ok {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					pod, ok = tombstone.Obj.(*corev1.Pod)
+					if !ok {
+						return
+					}
+				} else {
+					return
+				}
+			}
+			o.handlePodDelete(pod)
+		},
+	})
+
+	factory.Start(stopCh)
+	factory.WaitForCacheSync(stopCh)
+	<-stopCh
+}
+
+// resolvedTarget is one manifest variant handlePodUpsert has resolved for a
+// container, paired with the pod context it should be filed under once the
+// orchestrator's maps are updated under lock.
+type resolvedTarget struct {
+	key        string
+	canonical  string
+	target     manifestDescriptor
+	podContext *imagePodContext
+}
+
+// handlePodUpsert is invoked by the pod informer for AddFunc/UpdateFunc. It
+// allocates a new scanData (with a fresh scan UUID) for images that have not
+// been seen yet, queuing them for jobBatchManagement, and appends a pod
+// context to the existing scanData for images that are already known.
+func (o *Orchestrator) handlePodUpsert(pod *corev1.Pod) {
+	if shouldIgnorePod(pod, o.scanConfig.IgnoredNamespaces) {
+		return
+	}
+
+	// Resolution below may hit the registry (resolveScanTargets), so it runs
+	// unlocked; only the map/progress update at the end takes o.Lock.
+	secrets := k8s_utils.GetPodImagePullSecrets(o.clientset, *pod)
+
+	containerNameToImageId := make(map[string]string)
+	for _, container := range pod.Status.ContainerStatuses {
+		containerNameToImageId[container.Name] = container.ImageID
+	}
+
+	var resolved []resolvedTarget
+	for _, container := range pod.Spec.Containers {
+		pullSecret := k8s_utils.GetMatchingSecretName(secrets, container.Image)
+		imageHash := getImageHash(containerNameToImageId, container)
+		canonicalImage := imageref.Canonicalize(container.Image, o.scanConfig.RegistrySearch)
+
+		for _, target := range o.resolveScanTargets(canonicalImage, imageHash, pullSecret, pod.GetNamespace()) {
+			podContext := &imagePodContext{
+				imageName:       container.Image,
+				containerName:   container.Name,
+				podName:         pod.GetName(),
+				podUid:          string(pod.GetUID()),
+				namespace:       pod.GetNamespace(),
+				imagePullSecret: pullSecret,
+				imageHash:       target.digest,
+			}
+
+			resolved = append(resolved, resolvedTarget{
+				key:        scanKey(canonicalImage, target),
+				canonical:  canonicalImage,
+				target:     target,
+				podContext: podContext,
+			})
+		}
+	}
+
+	o.Lock()
+	defer o.Unlock()
+
+	o.reconcileSpeculativeTargets(pod, resolved)
+
+	for _, r := range resolved {
+		data, ok := o.imageToScanData[r.key]
+		if !ok {
+			data = &scanData{
+				imageName:    r.canonical,
+				digest:       r.target.digest,
+				architecture: r.target.architecture,
+				os:           r.target.os,
+				contexts:     []*imagePodContext{r.podContext},
+				scanUUID:     uuid.NewV4().String(),
+				resultChan:   make(chan bool),
+			}
+			o.imageToScanData[r.key] = data
+			o.scanUUIDToScanData[data.scanUUID] = data
+			atomic.AddUint32(&o.progress.ImagesToScan, 1)
+			o.queueScan(data)
+			continue
+		}
+
+		if !podAlreadyInContexts(data.contexts, r.podContext.podUid, r.podContext.containerName) {
+			data.contexts = append(data.contexts, r.podContext)
+		}
+	}
+}
+
+// reconcileSpeculativeTargets drops this pod's contexts from any scanData
+// outside the set of keys just resolved for it. It undoes the speculative
+// per-platform fan-out resolveScanTargets does while a pod's image hash is
+// still unknown: once a later UpdateFunc reports the real digest, the
+// manifest-list platforms the pod never actually runs would otherwise keep
+// their scanData around, get scanned, and count toward ImagesToScan until
+// the pod is deleted. Callers must hold o.Lock.
+func (o *Orchestrator) reconcileSpeculativeTargets(pod *corev1.Pod, resolved []resolvedTarget) {
+	podUid := string(pod.GetUID())
+
+	keepKeysByContainer := make(map[string]map[string]bool)
+	for _, r := range resolved {
+		containerName := r.podContext.containerName
+		keep, ok := keepKeysByContainer[containerName]
+		if !ok {
+			keep = make(map[string]bool)
+			keepKeysByContainer[containerName] = keep
+		}
+		keep[r.key] = true
+	}
+
+	for containerName, keep := range keepKeysByContainer {
+		for key, data := range o.imageToScanData {
+			if keep[key] {
+				continue
+			}
+			o.dropPodContainerContext(key, data, podUid, containerName)
+		}
+	}
+}
+
+// dropPodContainerContext removes (podUid, containerName)'s context from
+// data, retiring data once its last context is gone. Callers must hold
+// o.Lock.
+func (o *Orchestrator) dropPodContainerContext(key string, data *scanData, podUid, containerName string) {
+	remaining := data.contexts[:0]
+	removed := false
+	for _, ctx := range data.contexts {
+		if ctx.podUid == podUid && ctx.containerName == containerName {
+			removed = true
+			continue
+		}
+		remaining = append(remaining, ctx)
+	}
+	if !removed {
+		return
+	}
+	data.contexts = remaining
+
+	if len(data.contexts) == 0 {
+		o.retireScanData(key, data)
+	}
+}
+
+// retireScanData removes data from imageToScanData/scanUUIDToScanData and
+// stops its timeout timer. If data never received a result, it is reconciled
+// the same way a timed-out scan is: marked completed so a late result can't
+// resurrect it, backed out of ImagesToScan so progress can still reach
+// ImagesCompletedToScan, and its resultChan signaled so a jobBatchManagement
+// waiter for it isn't left blocked forever. Callers must hold o.Lock.
+func (o *Orchestrator) retireScanData(key string, data *scanData) {
+	if data.timeoutTimer != nil {
+		data.timeoutTimer.Stop()
+	}
+
+	if !data.completed {
+		data.completed = true
+		atomic.AddUint32(&o.progress.ImagesToScan, ^uint32(0))
+		select {
+		case data.resultChan <- true:
+		default:
+			log.Warnf("Failed to notify upon scan retirement. image=%v, scan-uuid=%v", data.imageName, data.scanUUID)
+		}
+	}
+
+	delete(o.imageToScanData, key)
+	delete(o.scanUUIDToScanData, data.scanUUID)
+}
+
+// handlePodDelete drops the pod's contexts from any scanData that reference
+// it, and retires the scanData entirely once its last context is gone.
+func (o *Orchestrator) handlePodDelete(pod *corev1.Pod) {
+	o.Lock()
+	defer o.Unlock()
+
+	podUid := string(pod.GetUID())
+
+	for image, data := range o.imageToScanData {
+		remaining := data.contexts[:0]
+		for _, ctx := range data.contexts {
+			if ctx.podUid != podUid {
+				remaining = append(remaining, ctx)
+			}
+		}
+		data.contexts = remaining
+
+		if len(data.contexts) == 0 {
+			o.retireScanData(image, data)
+		}
+	}
+}
+
+func podAlreadyInContexts(contexts []*imagePodContext, podUid, containerName string) bool {
+	for _, ctx := range contexts {
+		if ctx.podUid == podUid && ctx.containerName == containerName {
+			return true
+		}
+	}
+	return false
+}
+
 type ScanProgress struct {
 	ImagesToScan          uint32
 	ImagesStartedToScan   uint32
@@ -302,6 +912,14 @@ const (
 	ScanInit        Status = "ScanInit"
 	ScanInitFailure Status = "ScanInitFailure"
 	Scanning        Status = "Scanning"
+	// Watching is reported once the initial scan has been dispatched and the
+	// orchestrator is following the cluster via a pod informer, launching
+	// scans for newly observed images as they appear.
+	Watching Status = "Watching"
+	// ScanStalled means no scan result has been received in
+	// ScanConfig.OrchestratorIdleTimeout, even though scans are still
+	// outstanding. See Results() for the images still pending.
+	ScanStalled Status = "ScanStalled"
 )
 
 func (o *Orchestrator) Status() Status {
@@ -318,34 +936,51 @@ type ImageScanResult struct {
 	ContainerName   string
 	ImageHash       string
 	PodUid          string
-	Vulnerabilities []*clair.Vulnerability
+	Vulnerabilities []*scanner.ScanFinding
 	Success         bool
+	// Architecture, OS and Digest identify the manifest-list variant that
+	// was actually scanned, so results for different platform builds of the
+	// same tag aren't conflated.
+	Architecture string
+	OS           string
+	Digest       string
 }
 
 type ScanResults struct {
 	ImageScanResults []*ImageScanResult
 	Progress         ScanProgress
+	// StuckImages lists images that are still outstanding while the
+	// orchestrator is ScanStalled, so operators know which klar-scan pods to
+	// go investigate.
+	StuckImages []string
 }
 
 func (o *Orchestrator) Results() *ScanResults {
 	o.Lock()
 	defer o.Unlock()
 	var imageScanResults []*ImageScanResult
+	var stuckImages []string
 
 	for _, scanD := range o.imageToScanData {
 		if !scanD.completed {
+			if o.status == ScanStalled {
+				stuckImages = append(stuckImages, scanD.imageName)
+			}
 			continue
 		}
 		for _, context := range scanD.contexts {
 			imageScanResults = append(imageScanResults, &ImageScanResult{
 				PodName:         context.podName,
 				PodNamespace:    context.namespace,
-				ImageName:       scanD.imageName,
+				ImageName:       context.imageName,
 				ContainerName:   context.containerName,
 				ImageHash:       context.imageHash,
 				PodUid:          context.podUid,
 				Vulnerabilities: scanD.result,
 				Success:         scanD.success,
+				Architecture:    scanD.architecture,
+				OS:              scanD.os,
+				Digest:          scanD.digest,
 			})
 		}
 	}
@@ -353,6 +988,7 @@ func (o *Orchestrator) Results() *ScanResults {
 	return &ScanResults{
 		ImageScanResults: imageScanResults,
 		Progress:         o.ScanProgress(),
+		StuckImages:      stuckImages,
 	}
 }
 
@@ -360,9 +996,30 @@ func (o *Orchestrator) Clear() {
 	o.Lock()
 	defer o.Unlock()
 
+	if o.stopWatching != nil {
+		close(o.stopWatching)
+		o.stopWatching = nil
+	}
+	if o.stopStallMonitor != nil {
+		close(o.stopStallMonitor)
+		o.stopStallMonitor = nil
+	}
+	o.stopAllScanTimers()
+
 	o.imageToScanData = nil
+	o.scanUUIDToScanData = nil
 	o.progress = ScanProgress{}
 	o.status = Idle
 
 	return
 }
+
+// stopAllScanTimers cancels every in-flight per-image timeout timer. Callers
+// must hold o.Lock.
+func (o *Orchestrator) stopAllScanTimers() {
+	for _, data := range o.imageToScanData {
+		if data.timeoutTimer != nil {
+			data.timeoutTimer.Stop()
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Portshift/kubei/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newTestPod(uid string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{UID: types.UID(uid)}}
+}
+
+func TestHandlePodDeleteRetiresIncompleteScan(t *testing.T) {
+	data := &scanData{
+		imageName:  "nginx",
+		scanUUID:   "uuid-1",
+		resultChan: make(chan bool, 1),
+		contexts:   []*imagePodContext{{podUid: "pod-1", containerName: "app"}},
+	}
+
+	o := &Orchestrator{
+		imageToScanData:    map[string]*scanData{"nginx": data},
+		scanUUIDToScanData: map[string]*scanData{"uuid-1": data},
+		progress:           ScanProgress{ImagesToScan: 1},
+	}
+
+	o.handlePodDelete(newTestPod("pod-1"))
+
+	if _, ok := o.imageToScanData["nginx"]; ok {
+		t.Error("expected scanData to be removed from imageToScanData")
+	}
+	if _, ok := o.scanUUIDToScanData["uuid-1"]; ok {
+		t.Error("expected scanData to be removed from scanUUIDToScanData")
+	}
+	if o.progress.ImagesToScan != 0 {
+		t.Errorf("ImagesToScan = %d, want 0 once the only outstanding scan is deleted", o.progress.ImagesToScan)
+	}
+
+	select {
+	case <-data.resultChan:
+	default:
+		t.Error("expected resultChan to be signaled so a jobBatchManagement waiter isn't left blocked")
+	}
+}
+
+func TestHandlePodDeleteLeavesCompletedScanCountAlone(t *testing.T) {
+	data := &scanData{
+		imageName:  "nginx",
+		scanUUID:   "uuid-1",
+		completed:  true,
+		resultChan: make(chan bool, 1),
+		contexts:   []*imagePodContext{{podUid: "pod-1", containerName: "app"}},
+	}
+
+	o := &Orchestrator{
+		imageToScanData:    map[string]*scanData{"nginx": data},
+		scanUUIDToScanData: map[string]*scanData{"uuid-1": data},
+		progress:           ScanProgress{ImagesToScan: 1, ImagesCompletedToScan: 1},
+	}
+
+	o.handlePodDelete(newTestPod("pod-1"))
+
+	if o.progress.ImagesToScan != 1 {
+		t.Errorf("ImagesToScan = %d, want unchanged at 1 for an already-completed scan", o.progress.ImagesToScan)
+	}
+
+	select {
+	case <-data.resultChan:
+		t.Error("resultChan should not be re-signaled for an already-completed scan")
+	default:
+	}
+}
+
+func TestHandlePodDeleteKeepsScanDataWithRemainingContexts(t *testing.T) {
+	data := &scanData{
+		imageName:  "nginx",
+		scanUUID:   "uuid-1",
+		resultChan: make(chan bool, 1),
+		contexts: []*imagePodContext{
+			{podUid: "pod-1", containerName: "app"},
+			{podUid: "pod-2", containerName: "app"},
+		},
+	}
+
+	o := &Orchestrator{
+		imageToScanData:    map[string]*scanData{"nginx": data},
+		scanUUIDToScanData: map[string]*scanData{"uuid-1": data},
+		progress:           ScanProgress{ImagesToScan: 1},
+	}
+
+	o.handlePodDelete(newTestPod("pod-1"))
+
+	if _, ok := o.imageToScanData["nginx"]; !ok {
+		t.Fatal("expected scanData to remain while another pod still references it")
+	}
+	if len(data.contexts) != 1 || data.contexts[0].podUid != "pod-2" {
+		t.Errorf("contexts = %+v, want only pod-2's context left", data.contexts)
+	}
+	if o.progress.ImagesToScan != 1 {
+		t.Errorf("ImagesToScan = %d, want unchanged at 1", o.progress.ImagesToScan)
+	}
+}
+
+func TestHandleScanTimeoutReclaimsAsFailed(t *testing.T) {
+	data := &scanData{
+		imageName:  "nginx",
+		resultChan: make(chan bool, 1),
+	}
+	o := &Orchestrator{}
+
+	o.handleScanTimeout(data)
+
+	if !data.completed || data.success {
+		t.Fatalf("data = %+v, want completed=true success=false", data)
+	}
+	if len(data.result) != 1 || data.result[0].VulnerabilityID != scanTimeoutVulnerabilityID {
+		t.Errorf("result = %+v, want a single %q finding", data.result, scanTimeoutVulnerabilityID)
+	}
+
+	select {
+	case <-data.resultChan:
+	default:
+		t.Error("expected resultChan to be signaled")
+	}
+}
+
+func TestHandleScanTimeoutIsANoOpOnceCompleted(t *testing.T) {
+	data := &scanData{
+		imageName:  "nginx",
+		completed:  true,
+		success:    true,
+		resultChan: make(chan bool, 1),
+	}
+	o := &Orchestrator{}
+
+	// A normal result already landed first (e.g. the job finished just
+	// before its deadline); the timeout must not override it or signal
+	// resultChan a second time, which is what double-counted
+	// ImagesCompletedToScan before this was fixed.
+	o.handleScanTimeout(data)
+
+	if !data.success {
+		t.Error("handleScanTimeout must not flip an already-completed, successful scan to failed")
+	}
+
+	select {
+	case <-data.resultChan:
+		t.Error("resultChan should not be signaled for an already-completed scan")
+	default:
+	}
+}
+
+func TestMonitorStallFlipsStatusOnceIdle(t *testing.T) {
+	o := &Orchestrator{
+		scanConfig:   &config.ScanConfig{OrchestratorIdleTimeout: 20 * time.Millisecond},
+		status:       Scanning,
+		lastResultAt: time.Now().Add(-time.Hour),
+	}
+
+	stopCh := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		o.monitorStall(stopCh)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) && o.Status() != ScanStalled {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if got := o.Status(); got != ScanStalled {
+		t.Fatalf("status = %v, want %v", got, ScanStalled)
+	}
+
+	close(stopCh)
+	<-done
+}
+
+func TestReconcileSpeculativeTargetsDropsStaleFanOut(t *testing.T) {
+	pod := newTestPod("pod-1")
+
+	amd64Data := &scanData{
+		scanUUID:   "amd64-uuid",
+		resultChan: make(chan bool, 1),
+		contexts:   []*imagePodContext{{podUid: "pod-1", containerName: "app"}},
+	}
+	armData := &scanData{
+		scanUUID:   "arm-uuid",
+		resultChan: make(chan bool, 1),
+		contexts:   []*imagePodContext{{podUid: "pod-1", containerName: "app"}},
+	}
+
+	o := &Orchestrator{
+		imageToScanData: map[string]*scanData{
+			"sha256:amd64": amd64Data,
+			"sha256:arm":   armData,
+		},
+		scanUUIDToScanData: map[string]*scanData{
+			"amd64-uuid": amd64Data,
+			"arm-uuid":   armData,
+		},
+		progress: ScanProgress{ImagesToScan: 2},
+	}
+
+	// The pod's real digest has since resolved to amd64 only - the arm
+	// entry is leftover speculative fan-out from before ContainerStatuses
+	// reported a concrete image hash.
+	resolved := []resolvedTarget{
+		{key: "sha256:amd64", podContext: &imagePodContext{containerName: "app"}},
+	}
+
+	o.reconcileSpeculativeTargets(pod, resolved)
+
+	if _, ok := o.imageToScanData["sha256:amd64"]; !ok {
+		t.Error("expected the resolved variant to be kept")
+	}
+	if _, ok := o.imageToScanData["sha256:arm"]; ok {
+		t.Error("expected the stale speculative variant to be dropped")
+	}
+	if o.progress.ImagesToScan != 1 {
+		t.Errorf("ImagesToScan = %d, want 1 after dropping the stale variant", o.progress.ImagesToScan)
+	}
+
+	select {
+	case <-armData.resultChan:
+	default:
+		t.Error("expected the dropped variant's resultChan to be signaled")
+	}
+}
+
+func TestReconcileSpeculativeTargetsLeavesOtherContainersAlone(t *testing.T) {
+	pod := newTestPod("pod-1")
+
+	sidecarData := &scanData{
+		scanUUID:   "sidecar-uuid",
+		resultChan: make(chan bool, 1),
+		contexts:   []*imagePodContext{{podUid: "pod-1", containerName: "sidecar"}},
+	}
+
+	o := &Orchestrator{
+		imageToScanData:    map[string]*scanData{"sha256:sidecar": sidecarData},
+		scanUUIDToScanData: map[string]*scanData{"sidecar-uuid": sidecarData},
+		progress:           ScanProgress{ImagesToScan: 1},
+	}
+
+	// Only "app" resolved this round; "sidecar" wasn't touched, so its
+	// scanData must survive even though its key isn't in resolved.
+	resolved := []resolvedTarget{
+		{key: "sha256:app", podContext: &imagePodContext{containerName: "app"}},
+	}
+
+	o.reconcileSpeculativeTargets(pod, resolved)
+
+	if _, ok := o.imageToScanData["sha256:sidecar"]; !ok {
+		t.Error("expected the sidecar container's scanData to be left alone")
+	}
+	if o.progress.ImagesToScan != 1 {
+		t.Errorf("ImagesToScan = %d, want unchanged at 1", o.progress.ImagesToScan)
+	}
+}
@@ -0,0 +1,33 @@
+package orchestrator
+
+import "testing"
+
+func TestScanKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		imageName string
+		target    manifestDescriptor
+		want      string
+	}{
+		{
+			name:      "digest known uses digest",
+			imageName: "docker.io/library/nginx:latest",
+			target:    manifestDescriptor{digest: "sha256:abc123", architecture: "amd64", os: "linux"},
+			want:      "sha256:abc123",
+		},
+		{
+			name:      "digest unknown falls back to image name",
+			imageName: "docker.io/library/nginx:latest",
+			target:    manifestDescriptor{},
+			want:      "docker.io/library/nginx:latest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scanKey(tt.imageName, tt.target); got != tt.want {
+				t.Errorf("scanKey(%q, %+v) = %q, want %q", tt.imageName, tt.target, got, tt.want)
+			}
+		})
+	}
+}
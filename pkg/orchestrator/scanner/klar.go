@@ -0,0 +1,62 @@
+package scanner
+
+import (
+	"github.com/Portshift/klar/clair"
+	k8s_utils "github.com/Portshift/kubei/pkg/utils/k8s"
+)
+
+func init() {
+	Register(&klarScanner{})
+}
+
+// klarScanner is the original backend: it launches a klar-scan job pod that
+// talks to a Clair server and POSTs its native vulnerability shape back to
+// the orchestrator.
+type klarScanner struct{}
+
+func (k *klarScanner) Name() string {
+	return "klar"
+}
+
+func (k *klarScanner) LaunchJob(target *ScanTarget) error {
+	return k8s_utils.CreateKlarJob(target.ImageName, target.ScanUUID, target.ImagePullSecret)
+}
+
+func (k *klarScanner) ResultSchema() interface{} {
+	return &klarResult{}
+}
+
+// klarResult mirrors github.com/Portshift/klar/forwarding.ImageVulnerabilities,
+// the payload klar-scan has always POSTed back.
+type klarResult struct {
+	Image           string                 `json:"image"`
+	ScanUUID        string                 `json:"scan_uuid"`
+	Success         bool                   `json:"success"`
+	Vulnerabilities []*clair.Vulnerability `json:"vulnerabilities"`
+}
+
+func (r *klarResult) ScanImage() string { return r.Image }
+func (r *klarResult) ScanID() string    { return r.ScanUUID }
+func (r *klarResult) ScanSuccess() bool { return r.Success }
+
+func (k *klarScanner) Normalize(raw interface{}) []*ScanFinding {
+	res, ok := raw.(*klarResult)
+	if !ok || res == nil {
+		return nil
+	}
+
+	findings := make([]*ScanFinding, 0, len(res.Vulnerabilities))
+	for _, v := range res.Vulnerabilities {
+		findings = append(findings, &ScanFinding{
+			VulnerabilityID: v.Name,
+			PackageName:     v.FeatureName,
+			PackageVersion:  v.FeatureVersion,
+			FixedVersion:    v.FixedBy,
+			Severity:        v.Severity,
+			Description:     v.Description,
+			Link:            v.Link,
+		})
+	}
+
+	return findings
+}
@@ -0,0 +1,88 @@
+// Package scanner decouples the orchestrator from any one vulnerability
+// scanning backend. A Scanner knows how to launch a scan job for an image
+// and how to turn whatever that job posts back into the orchestrator's
+// neutral ScanFinding shape; the orchestrator never needs to know whether
+// it's talking to Klar/Clair, Trivy, or anything else.
+package scanner
+
+import "fmt"
+
+// DefaultBackendName is used when ScanConfig.ScannerBackend is unset, so
+// existing deployments keep scanning with Klar/Clair without changes.
+const DefaultBackendName = "klar"
+
+// ScanFinding is the orchestrator's backend-agnostic view of a single
+// vulnerability, independent of how the underlying Scanner reports it.
+type ScanFinding struct {
+	VulnerabilityID string
+	PackageName     string
+	PackageVersion  string
+	FixedVersion    string
+	Severity        string
+	Description     string
+	Link            string
+}
+
+// ScanTarget carries what a Scanner needs to launch a job for one image.
+type ScanTarget struct {
+	ImageName       string
+	ScanUUID        string
+	ImagePullSecret string
+	// Namespace is where the scan job itself should be created, so it can
+	// reach ImagePullSecret (a namespaced object).
+	Namespace string
+	// ResultServiceAddress is the host:port a scan job's result callback
+	// should POST to, i.e. config.Config.ResultServiceAddress.
+	ResultServiceAddress string
+}
+
+// ResultMeta is implemented by every backend's decoded result struct, so the
+// orchestrator's HTTP handler can route a result to the right scanData
+// without knowing anything backend-specific about its shape.
+type ResultMeta interface {
+	ScanImage() string
+	ScanID() string
+	ScanSuccess() bool
+}
+
+// Scanner is implemented by every pluggable vulnerability scanning backend.
+// The orchestrator launches one job per ScanTarget via LaunchJob and, once
+// the backend POSTs a result back to /result/{Name}/, decodes the body into
+// ResultSchema and converts it with Normalize.
+type Scanner interface {
+	// Name identifies the backend; it is also the path segment the result
+	// callback is registered under.
+	Name() string
+	// LaunchJob starts whatever the backend needs (a klar-scan pod, a Trivy
+	// job, ...) to scan target and POST its result back to the orchestrator.
+	LaunchJob(target *ScanTarget) error
+	// ResultSchema returns a pointer the HTTP handler can decode a result
+	// payload into. The returned value must also implement ResultMeta.
+	ResultSchema() interface{}
+	// Normalize converts a decoded result (as produced by ResultSchema) into
+	// the orchestrator's backend-agnostic finding list.
+	Normalize(raw interface{}) []*ScanFinding
+}
+
+var registry = map[string]Scanner{}
+
+// Register makes a Scanner available by name for ScanConfig.ScannerBackend.
+// Backends call this from an init func, mirroring database/sql drivers.
+func Register(s Scanner) {
+	registry[s.Name()] = s
+}
+
+// Get looks up a registered Scanner by name, falling back to
+// DefaultBackendName when name is empty.
+func Get(name string) (Scanner, error) {
+	if name == "" {
+		name = DefaultBackendName
+	}
+
+	s, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("no scanner backend registered with name %q", name)
+	}
+
+	return s, nil
+}
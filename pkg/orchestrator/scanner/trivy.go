@@ -0,0 +1,84 @@
+package scanner
+
+import (
+	k8s_utils "github.com/Portshift/kubei/pkg/utils/k8s"
+)
+
+func init() {
+	Register(&trivyScanner{})
+}
+
+// trivyScanner launches a Trivy job pod that scans an image and POSTs back
+// a thin envelope (image/scan UUID/success) wrapping Trivy's own JSON report
+// format, for clusters that have already standardized on Trivy over Klar.
+type trivyScanner struct{}
+
+func (t *trivyScanner) Name() string {
+	return "trivy"
+}
+
+func (t *trivyScanner) LaunchJob(target *ScanTarget) error {
+	return k8s_utils.CreateTrivyJob(target.ImageName, target.Namespace, target.ScanUUID, target.ImagePullSecret, target.ResultServiceAddress)
+}
+
+func (t *trivyScanner) ResultSchema() interface{} {
+	return &trivyResult{}
+}
+
+// trivyResult wraps Trivy's native `trivy image -f json` report (nested
+// under "report", unmodified) with the envelope fields the orchestrator
+// needs to route the result - see k8s_utils.trivyScanScript, which is what
+// actually builds this shape from inside the trivy-scan job.
+type trivyResult struct {
+	Image    string      `json:"image"`
+	ScanUUID string      `json:"scan_uuid"`
+	Success  bool        `json:"success"`
+	Report   trivyReport `json:"report"`
+}
+
+type trivyReport struct {
+	Results []trivyTargetResult `json:"Results"`
+}
+
+type trivyTargetResult struct {
+	Target          string               `json:"Target"`
+	Vulnerabilities []trivyVulnerability `json:"Vulnerabilities"`
+}
+
+type trivyVulnerability struct {
+	VulnerabilityID  string `json:"VulnerabilityID"`
+	PkgName          string `json:"PkgName"`
+	InstalledVersion string `json:"InstalledVersion"`
+	FixedVersion     string `json:"FixedVersion"`
+	Severity         string `json:"Severity"`
+	Description      string `json:"Description"`
+	PrimaryURL       string `json:"PrimaryURL"`
+}
+
+func (r *trivyResult) ScanImage() string { return r.Image }
+func (r *trivyResult) ScanID() string    { return r.ScanUUID }
+func (r *trivyResult) ScanSuccess() bool { return r.Success }
+
+func (t *trivyScanner) Normalize(raw interface{}) []*ScanFinding {
+	res, ok := raw.(*trivyResult)
+	if !ok || res == nil {
+		return nil
+	}
+
+	var findings []*ScanFinding
+	for _, target := range res.Report.Results {
+		for _, v := range target.Vulnerabilities {
+			findings = append(findings, &ScanFinding{
+				VulnerabilityID: v.VulnerabilityID,
+				PackageName:     v.PkgName,
+				PackageVersion:  v.InstalledVersion,
+				FixedVersion:    v.FixedVersion,
+				Severity:        v.Severity,
+				Description:     v.Description,
+				Link:            v.PrimaryURL,
+			})
+		}
+	}
+
+	return findings
+}
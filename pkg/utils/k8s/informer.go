@@ -0,0 +1,15 @@
+package k8s_utils
+
+import (
+	"time"
+
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+)
+
+// NewPodSharedInformerFactory returns a SharedInformerFactory scoped to
+// namespace and configured with resyncPeriod, for callers that only need to
+// watch pods (the orchestrator's continuous scan mode).
+func NewPodSharedInformerFactory(clientset kubernetes.Interface, namespace string, resyncPeriod time.Duration) informers.SharedInformerFactory {
+	return informers.NewSharedInformerFactoryWithOptions(clientset, resyncPeriod, informers.WithNamespace(namespace))
+}
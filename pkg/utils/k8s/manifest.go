@@ -0,0 +1,158 @@
+package k8s_utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ManifestDescriptor identifies one platform-specific manifest referenced by
+// a multi-arch image's manifest list/OCI index.
+type ManifestDescriptor struct {
+	Digest       string
+	Architecture string
+	OS           string
+}
+
+// ResolveImageManifestDescriptors fetches imageName's manifest from its
+// registry and, if it is a manifest list/OCI index, returns one descriptor
+// per platform it advertises. A single-platform manifest yields one
+// descriptor for its own digest.
+//
+// When pullSecret names a dockerconfigjson Secret in namespace, its
+// credentials for the reference's registry are used to authenticate;
+// otherwise resolution proceeds anonymously, the same as an anonymous
+// `docker pull`.
+func ResolveImageManifestDescriptors(clientset kubernetes.Interface, imageName, namespace, pullSecret string) ([]ManifestDescriptor, error) {
+	ref, err := name.ParseReference(imageName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse image reference %q: %v", imageName, err)
+	}
+
+	auth, err := authenticatorFor(clientset, ref, namespace, pullSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve auth for image pull secret %q: %v", pullSecret, err)
+	}
+
+	desc, err := remote.Get(ref, remote.WithAuth(auth))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifest for %q: %v", imageName, err)
+	}
+
+	if !desc.MediaType.IsIndex() {
+		return []ManifestDescriptor{{Digest: desc.Digest.String()}}, nil
+	}
+
+	index, err := desc.ImageIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest list for %q: %v", imageName, err)
+	}
+
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest list entries for %q: %v", imageName, err)
+	}
+
+	descriptors := make([]ManifestDescriptor, 0, len(manifest.Manifests))
+	for _, m := range manifest.Manifests {
+		descriptors = append(descriptors, manifestDescriptorFrom(m))
+	}
+
+	return descriptors, nil
+}
+
+func manifestDescriptorFrom(m v1.Descriptor) ManifestDescriptor {
+	d := ManifestDescriptor{Digest: m.Digest.String()}
+	if m.Platform != nil {
+		d.Architecture = m.Platform.Architecture
+		d.OS = m.Platform.OS
+	}
+	return d
+}
+
+// authenticatorFor resolves the credentials pullSecret (a dockerconfigjson
+// Secret in namespace) holds for ref's registry. An empty pullSecret, or one
+// with no entry matching the registry, resolves to authn.Anonymous so
+// resolution still proceeds for public images.
+func authenticatorFor(clientset kubernetes.Interface, ref name.Reference, namespace, pullSecret string) (authn.Authenticator, error) {
+	if pullSecret == "" {
+		return authn.Anonymous, nil
+	}
+
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(pullSecret, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret %q in namespace %q: %v", pullSecret, namespace, err)
+	}
+
+	auth, ok, err := authConfigFromDockerConfigJSON(secret, ref.Context().RegistryStr())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return authn.Anonymous, nil
+	}
+
+	return authn.FromConfig(auth), nil
+}
+
+type dockerConfigJSON struct {
+	Auths map[string]struct {
+		Auth     string `json:"auth"`
+		Username string `json:"username"`
+		Password string `json:"password"`
+	} `json:"auths"`
+}
+
+// authConfigFromDockerConfigJSON reads secret's .dockerconfigjson payload
+// and returns the entry matching registry, if any.
+func authConfigFromDockerConfigJSON(secret *corev1.Secret, registry string) (authn.AuthConfig, bool, error) {
+	raw, ok := secret.Data[corev1.DockerConfigJsonKey]
+	if !ok {
+		return authn.AuthConfig{}, false, fmt.Errorf("secret %q is not a %s secret", secret.Name, corev1.SecretTypeDockerConfigJson)
+	}
+
+	var cfg dockerConfigJSON
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return authn.AuthConfig{}, false, fmt.Errorf("failed to parse %s: %v", corev1.DockerConfigJsonKey, err)
+	}
+
+	for host, entry := range cfg.Auths {
+		if !registryHostMatches(host, registry) {
+			continue
+		}
+
+		username, password := entry.Username, entry.Password
+		if username == "" && password == "" && entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return authn.AuthConfig{}, false, fmt.Errorf("failed to decode auth for %q: %v", host, err)
+			}
+			if u, p, found := strings.Cut(string(decoded), ":"); found {
+				username, password = u, p
+			}
+		}
+
+		return authn.AuthConfig{Username: username, Password: password}, true, nil
+	}
+
+	return authn.AuthConfig{}, false, nil
+}
+
+// registryHostMatches compares a docker config auths key (which may be a
+// bare host or a full registry URL, e.g. "https://index.docker.io/v1/") to
+// the registry host go-containerregistry resolved the reference to.
+func registryHostMatches(configHost, registry string) bool {
+	host := strings.TrimPrefix(strings.TrimPrefix(configHost, "https://"), "http://")
+	host = strings.TrimSuffix(host, "/v1/")
+	host = strings.TrimSuffix(host, "/")
+	return host == registry
+}
@@ -0,0 +1,90 @@
+package k8s_utils
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const trivyJobContainerName = "trivy-scan"
+
+// trivyScanScript runs trivy against SCAN_IMAGE, wraps its native JSON
+// report in the envelope trivyResult expects (image/scan_uuid/success
+// alongside the raw report), and POSTs that envelope to the orchestrator's
+// /result/trivy/ endpoint at RESULT_SERVICE_ADDR. The trivy image itself
+// never talks to the orchestrator, so without this wrapper no trivy result
+// would arrive and every trivy scan would end in the per-image timeout.
+const trivyScanScript = `set -eu
+if trivy image --format json -o /tmp/trivy-report.json "$SCAN_IMAGE"; then
+  SUCCESS=true
+else
+  SUCCESS=false
+  echo '{"Results":[]}' > /tmp/trivy-report.json
+fi
+printf '{"image":"%s","scan_uuid":"%s","success":%s,"report":%s}' \
+  "$SCAN_IMAGE" "$SCAN_UUID" "$SUCCESS" "$(cat /tmp/trivy-report.json)" > /tmp/trivy-result.json
+curl -sS -X POST -H "Content-Type: application/json" \
+  --data-binary @/tmp/trivy-result.json "http://$RESULT_SERVICE_ADDR/result/trivy/"
+`
+
+// CreateTrivyJob launches a trivy-scan Job, in namespace, against imageName,
+// passing it the scan UUID, pull secret and result callback address it
+// needs to report back to the orchestrator's /result/trivy/ endpoint,
+// mirroring how CreateKlarJob launches the Klar/Clair backend's job.
+func CreateTrivyJob(imageName, namespace, scanUUID, imagePullSecret, resultServiceAddress string) error {
+	clientset, err := CreateClientset()
+	if err != nil {
+		return fmt.Errorf("failed to create clientset: %v", err)
+	}
+
+	job := newTrivyJob(imageName, namespace, scanUUID, imagePullSecret, resultServiceAddress)
+
+	if _, err := clientset.BatchV1().Jobs(job.Namespace).Create(job); err != nil {
+		return fmt.Errorf("failed to create trivy job for image %v: %v", imageName, err)
+	}
+
+	return nil
+}
+
+func newTrivyJob(imageName, namespace, scanUUID, imagePullSecret, resultServiceAddress string) *batchv1.Job {
+	var imagePullSecrets []corev1.LocalObjectReference
+	if imagePullSecret != "" {
+		imagePullSecrets = append(imagePullSecrets, corev1.LocalObjectReference{Name: imagePullSecret})
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "trivy-scan-",
+			Namespace:    namespace,
+			Labels:       map[string]string{"app": trivyJobContainerName, "scan-uuid": scanUUID},
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": trivyJobContainerName, "scan-uuid": scanUUID},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:    trivyJobContainerName,
+							Image:   "aquasec/trivy:latest",
+							Command: []string{"sh", "-c", trivyScanScript},
+							Env: []corev1.EnvVar{
+								{Name: "SCAN_UUID", Value: scanUUID},
+								{Name: "SCAN_IMAGE", Value: imageName},
+								{Name: "RESULT_SERVICE_ADDR", Value: resultServiceAddress},
+							},
+						},
+					},
+					ImagePullSecrets: imagePullSecrets,
+					RestartPolicy:    corev1.RestartPolicyNever,
+				},
+			},
+			BackoffLimit: int32Ptr(0),
+		},
+	}
+}
+
+func int32Ptr(i int32) *int32 { return &i }